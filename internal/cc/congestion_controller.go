@@ -0,0 +1,117 @@
+package cc
+
+import (
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// CongestionController is implemented by pluggable congestion control
+// algorithms (GCC, loss-based, NADA, ...) that consume the Acknowledgments
+// resolved from TWCC/RFC 8888 feedback and report back a target send
+// bitrate. Implementations must be safe for concurrent use, since
+// Interceptor calls them from both the RTCP read path and the RTP write
+// path.
+type CongestionController interface {
+	// OnAcknowledgments is called with the Acknowledgments resolved from a
+	// single incoming feedback report, in the order the report lists them:
+	// transport sequence order for TWCC, and per media SSRC in RTP sequence
+	// order for RFC 8888. A zero Acknowledgment.Arrival means the packet was
+	// reported lost; for TWCC feedback, a packet the FeedbackAdapter no
+	// longer holds in its history is also passed as an all-zero
+	// Acknowledgment.
+	OnAcknowledgments([]Acknowledgment)
+
+	// OnSent is called for every outgoing packet, before feedback about it
+	// can possibly have arrived.
+	OnSent(Acknowledgment)
+
+	// TargetBitrate returns the most recently computed target send bitrate,
+	// in bits per second.
+	TargetBitrate() int
+
+	// OnRTCP is called with every incoming RTCP packet, including ones
+	// Interceptor does not itself resolve into Acknowledgments (e.g.
+	// Receiver Reports or REMB), so controllers that fold in additional
+	// signals can observe them.
+	OnRTCP(rtcp.Packet)
+}
+
+// Interceptor resolves incoming TWCC and/or RFC 8888 feedback into
+// Acknowledgments via a FeedbackAdapter and forwards them to a registered
+// CongestionController, so the same controller can drive rate control
+// regardless of which feedback format the remote peer sends. It implements
+// interceptor.Interceptor so it can be installed directly into a
+// pion/webrtc InterceptorRegistry.
+type Interceptor struct {
+	interceptor.NoOp
+
+	adapter    *FeedbackAdapter
+	controller CongestionController
+}
+
+// NewInterceptor returns an Interceptor that drives controller with
+// Acknowledgments produced by adapter.
+func NewInterceptor(adapter *FeedbackAdapter, controller CongestionController) *Interceptor {
+	return &Interceptor{adapter: adapter, controller: controller}
+}
+
+// BindRTCPReader lets the Interceptor observe incoming RTCP packets so it can
+// resolve TWCC/RFC 8888 feedback into Acknowledgments for the controller.
+func (i *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+
+		packets, unmarshalErr := rtcp.Unmarshal(b[:n])
+		if unmarshalErr != nil {
+			return n, attr, nil
+		}
+
+		now := time.Now()
+		for _, packet := range packets {
+			i.controller.OnRTCP(packet)
+
+			switch feedback := packet.(type) {
+			case *rtcp.TransportLayerCC:
+				if acks, err := i.adapter.OnTransportCCFeedback(now, feedback); err == nil {
+					i.controller.OnAcknowledgments(acks)
+				}
+			case *rtcp.CCFeedbackReport:
+				if acks, err := i.adapter.OnRFC8888Feedback(now, feedback); err == nil {
+					i.controller.OnAcknowledgments(acks)
+				}
+			}
+		}
+
+		return n, attr, nil
+	})
+}
+
+// BindLocalStream lets the Interceptor record outgoing packets so they can
+// later be resolved against feedback, and notifies the controller of each
+// send.
+func (i *Interceptor) BindLocalStream(_ *interceptor.StreamInfo, writer interceptor.RTPWriter) interceptor.RTPWriter {
+	return interceptor.RTPWriterFunc(func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+		now := time.Now()
+		n, err := writer.Write(header, payload, attributes)
+		if err != nil {
+			return n, err
+		}
+
+		if sentErr := i.adapter.OnSent(now, header, len(payload), attributes); sentErr == nil {
+			i.controller.OnSent(Acknowledgment{
+				SSRC:           header.SSRC,
+				SequenceNumber: header.SequenceNumber,
+				Size:           header.MarshalSize() + len(payload),
+				Departure:      now,
+			})
+		}
+
+		return n, nil
+	})
+}
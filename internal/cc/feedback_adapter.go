@@ -21,53 +21,153 @@ var (
 	errInvalidFeedback        = errors.New("invalid feedback")
 )
 
+// Acknowledgment is the fate of a single sent RTP packet, resolved from
+// either TWCC or RFC 8888 feedback.
+type Acknowledgment struct {
+	// SSRC and SequenceNumber identify the RTP packet this Acknowledgment
+	// describes, and are always populated by OnSent.
+	SSRC           uint32
+	SequenceNumber uint16
+
+	// TLCC is the transport-wide congestion control sequence number used to
+	// correlate this packet with TWCC feedback, extended to 32 bits so it
+	// keeps increasing across the 16-bit wire value's rollovers. It is only
+	// meaningful when HasTLCC is true.
+	TLCC    uint32
+	HasTLCC bool
+
+	Size      int
+	Departure time.Time
+	Arrival   time.Time
+	RTT       time.Duration
+}
+
 // FeedbackAdapter converts incoming RTCP Packets (TWCC and RFC8888) into Acknowledgments.
 // Acknowledgments are the common format that Congestion Controllers in Pion understand.
 type FeedbackAdapter struct {
-	lock    sync.Mutex
+	lock sync.Mutex
+
 	history *feedbackHistory
+
+	// rfc8888 allows OnSent to record packets that carry no TWCC header
+	// extension, so they can later be resolved by OnRFC8888Feedback.
+	rfc8888 bool
+
+	// tlccCycles and lastTLCC track rollovers of the 16-bit TWCC transport
+	// sequence number the same way twcc.Recorder does, so sent packets can be
+	// keyed by an ever-increasing 32-bit sequence instead of colliding once a
+	// session crosses 65535 TWCC-tagged packets.
+	tlccCycles   uint32
+	lastTLCC     uint16
+	haveLastTLCC bool
+}
+
+// feedbackAdapterDefaultMaxEntries is the history size NewFeedbackAdapter
+// uses unless overridden by WithFeedbackAdapterHistory.
+const feedbackAdapterDefaultMaxEntries = 250
+
+// FeedbackAdapterOption configures a FeedbackAdapter.
+type FeedbackAdapterOption func(*FeedbackAdapter)
+
+// WithFeedbackAdapterRFC8888 enables resolving RFC 8888 (CCFB) feedback in
+// addition to TWCC. OnSent no longer requires a TWCC header extension to be
+// present, since RFC 8888 reports are joined against the standard RTP
+// SSRC/sequence number instead.
+func WithFeedbackAdapterRFC8888() FeedbackAdapterOption {
+	return func(f *FeedbackAdapter) { f.rfc8888 = true }
 }
 
-// NewFeedbackAdapter returns a new FeedbackAdapter
-func NewFeedbackAdapter() *FeedbackAdapter {
-	return &FeedbackAdapter{history: newFeedbackHistory(250)}
+// WithFeedbackAdapterHistory bounds the history's size by maxEntries and, if
+// maxAge is nonzero, evicts entries once they are older than maxAge as well.
+// maxAge is typically derived from the connection's smoothed RTT (e.g. 3x
+// smoothed RTT, floored to 500ms), so that acks are kept around for roughly
+// as long as feedback about them could plausibly still be outstanding,
+// whatever the bitrate. A maxAge of 0 disables age-based eviction and falls
+// back to the maxEntries cap alone.
+func WithFeedbackAdapterHistory(maxAge time.Duration, maxEntries int) FeedbackAdapterOption {
+	return func(f *FeedbackAdapter) { f.history = newFeedbackHistory(maxEntries, maxAge) }
+}
+
+// NewFeedbackAdapter returns a new FeedbackAdapter, recording up to
+// feedbackAdapterDefaultMaxEntries sent packets awaiting feedback unless
+// WithFeedbackAdapterHistory overrides it.
+func NewFeedbackAdapter(opts ...FeedbackAdapterOption) *FeedbackAdapter {
+	f := &FeedbackAdapter{history: newFeedbackHistory(feedbackAdapterDefaultMaxEntries, 0)}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 // OnSent records that and when an outgoing packet was sent for later mapping to
 // acknowledgments
 func (f *FeedbackAdapter) OnSent(ts time.Time, header *rtp.Header, size int, attributes interceptor.Attributes) error {
+	ack := Acknowledgment{
+		SSRC:           header.SSRC,
+		SequenceNumber: header.SequenceNumber,
+		Size:           header.MarshalSize() + size,
+		Departure:      ts,
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	hdrExtensionID := attributes.Get(TwccExtensionAttributesKey)
 	id, ok := hdrExtensionID.(uint8)
-	if !ok || hdrExtensionID == 0 {
+	switch {
+	case ok && hdrExtensionID != 0:
+		var tccExt rtp.TransportCCExtension
+		if err := tccExt.Unmarshal(header.GetExtension(id)); err != nil {
+			return errMissingTWCCExtension
+		}
+		ack.TLCC = f.extendTLCC(tccExt.TransportSequence)
+		ack.HasTLCC = true
+	case !f.rfc8888:
 		return errMissingTWCCExtensionID
 	}
-	sequenceNumber := header.GetExtension(id)
-	var tccExt rtp.TransportCCExtension
-	err := tccExt.Unmarshal(sequenceNumber)
-	if err != nil {
-		return errMissingTWCCExtension
-	}
 
-	f.lock.Lock()
-	defer f.lock.Unlock()
-	f.history.add(Acknowledgment{
-		TLCC:      tccExt.TransportSequence,
-		Size:      header.MarshalSize() + size,
-		Departure: ts,
-		Arrival:   time.Time{},
-		RTT:       0,
-	})
+	f.history.add(ack)
 	return nil
 }
 
-func (f *FeedbackAdapter) unpackRunLengthChunk(ts time.Time, start uint16, refTime time.Time, chunk *rtcp.RunLengthChunk, deltas []*rtcp.RecvDelta) (consumedDeltas int, nextRef time.Time, acks []Acknowledgment, err error) {
+// extendTLCC tracks rollovers of the 16-bit TWCC transport sequence number
+// the same way twcc.Recorder does: a cycle is counted every time a newly
+// observed sequence number wraps back around to near zero after the
+// previous one was near the top of the range. Must be called with f.lock
+// held, and only from OnSent, since it is the sender that assigns
+// ever-increasing TLCC values and is therefore the source of truth for when
+// a rollover happened.
+func (f *FeedbackAdapter) extendTLCC(sequenceNumber uint16) uint32 {
+	if f.haveLastTLCC && sequenceNumber < 0x0fff && f.lastTLCC > 0xf000 {
+		f.tlccCycles += 1 << 16
+	}
+	f.lastTLCC = sequenceNumber
+	f.haveLastTLCC = true
+	return f.tlccCycles + uint32(sequenceNumber)
+}
+
+// reconstructTLCC extends a 16-bit TWCC base sequence number from incoming
+// feedback into the same 32-bit space as extendTLCC, using the most recently
+// observed cycle count as the reference. Feedback can be delayed enough to
+// still describe packets sent before the latest rollover observed by
+// OnSent, so the base is pulled back a cycle when it looks like it landed
+// just before that rollover rather than just after it.
+func (f *FeedbackAdapter) reconstructTLCC(baseSequenceNumber uint16) uint32 {
+	cycles := f.tlccCycles
+	if f.haveLastTLCC && baseSequenceNumber > 0xf000 && f.lastTLCC < 0x0fff {
+		cycles -= 1 << 16
+	}
+	return cycles + uint32(baseSequenceNumber)
+}
+
+func (f *FeedbackAdapter) unpackRunLengthChunk(ts time.Time, start uint32, refTime time.Time, chunk *rtcp.RunLengthChunk, deltas []*rtcp.RecvDelta) (consumedDeltas int, nextRef time.Time, acks []Acknowledgment, err error) {
 	result := make([]Acknowledgment, chunk.RunLength)
 	deltaIndex := 0
 
-	end := start + chunk.RunLength
+	end := start + uint32(chunk.RunLength)
 	resultIndex := 0
 	for i := start; i != end; i++ {
-		if ack, ok := f.history.get(i); ok {
+		if ack, ok := f.history.get(i, ts); ok {
 			if chunk.PacketStatusSymbol != rtcp.TypeTCCPacketNotReceived {
 				if len(deltas)-1 < deltaIndex {
 					return deltaIndex, refTime, result, errInvalidFeedback
@@ -84,12 +184,12 @@ func (f *FeedbackAdapter) unpackRunLengthChunk(ts time.Time, start uint16, refTi
 	return deltaIndex, refTime, result, nil
 }
 
-func (f *FeedbackAdapter) unpackStatusVectorChunk(ts time.Time, start uint16, refTime time.Time, chunk *rtcp.StatusVectorChunk, deltas []*rtcp.RecvDelta) (consumedDeltas int, nextRef time.Time, acks []Acknowledgment, err error) {
+func (f *FeedbackAdapter) unpackStatusVectorChunk(ts time.Time, start uint32, refTime time.Time, chunk *rtcp.StatusVectorChunk, deltas []*rtcp.RecvDelta) (consumedDeltas int, nextRef time.Time, acks []Acknowledgment, err error) {
 	result := make([]Acknowledgment, len(chunk.SymbolList))
 	deltaIndex := 0
 	resultIndex := 0
 	for i, symbol := range chunk.SymbolList {
-		if ack, ok := f.history.get(start + uint16(i)); ok {
+		if ack, ok := f.history.get(start+uint32(i), ts); ok {
 			if symbol != rtcp.TypeTCCPacketNotReceived {
 				if len(deltas)-1 < deltaIndex {
 					return deltaIndex, refTime, result, errInvalidFeedback
@@ -114,7 +214,7 @@ func (f *FeedbackAdapter) OnTransportCCFeedback(ts time.Time, feedback *rtcp.Tra
 	defer f.lock.Unlock()
 
 	result := []Acknowledgment{}
-	index := feedback.BaseSequenceNumber
+	index := f.reconstructTLCC(feedback.BaseSequenceNumber)
 	refTime := time.Time{}.Add(time.Duration(feedback.ReferenceTime) * 64 * time.Millisecond)
 	recvDeltas := feedback.RecvDeltas
 
@@ -128,7 +228,7 @@ func (f *FeedbackAdapter) OnTransportCCFeedback(ts time.Time, feedback *rtcp.Tra
 			refTime = nextRefTime
 			result = append(result, acks...)
 			recvDeltas = recvDeltas[n:]
-			index = uint16(int(index) + len(acks))
+			index += uint32(len(acks))
 		case *rtcp.StatusVectorChunk:
 			n, nextRefTime, acks, err := f.unpackStatusVectorChunk(ts, index, refTime, chunk, recvDeltas)
 			if err != nil {
@@ -137,7 +237,7 @@ func (f *FeedbackAdapter) OnTransportCCFeedback(ts time.Time, feedback *rtcp.Tra
 			refTime = nextRefTime
 			result = append(result, acks...)
 			recvDeltas = recvDeltas[n:]
-			index = uint16(int(index) + len(acks))
+			index += uint32(len(acks))
 		default:
 			return nil, errInvalidFeedback
 		}
@@ -146,21 +246,101 @@ func (f *FeedbackAdapter) OnTransportCCFeedback(ts time.Time, feedback *rtcp.Tra
 	return result, nil
 }
 
+// OnRFC8888Feedback converts an incoming RFC 8888 (CCFB) RTCP packet into
+// Acknowledgments, resolved against the RTP SSRC/sequence number recorded by
+// OnSent rather than the TWCC transport sequence.
+func (f *FeedbackAdapter) OnRFC8888Feedback(ts time.Time, feedback *rtcp.CCFeedbackReport) ([]Acknowledgment, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	refTime := ntpShortToTime(feedback.ReportTimestamp)
+	result := []Acknowledgment{}
+
+	for _, block := range feedback.ReportBlocks {
+		seq := block.BeginSequence
+		for _, metric := range block.MetricBlocks {
+			ack, ok := f.history.getBySSRCSequence(block.MediaSSRC, seq, ts)
+			seq++
+			if !ok {
+				continue
+			}
+			if metric.Received {
+				ato := time.Duration(metric.ArrivalTimeOffset) * time.Second / 1024
+				ack.Arrival = refTime.Add(-ato)
+				ack.RTT = ts.Sub(ack.Departure)
+			}
+			result = append(result, ack)
+		}
+	}
+
+	return result, nil
+}
+
+// ntpShortToTime converts a 32-bit compact NTP timestamp (16.16 fixed point
+// seconds, as used by the RFC 8888 report timestamp field) to a time.Time
+// that many seconds after the zero time.Time. The whole seconds beyond the
+// 16-bit field are not recoverable, so only differences between results are
+// meaningful.
+func ntpShortToTime(compact uint32) time.Time {
+	seconds := compact >> 16
+	fraction := compact & 0xffff
+	return time.Time{}.Add(time.Duration(seconds)*time.Second + time.Duration(fraction)*time.Second/(1<<16))
+}
+
+type ssrcSequence struct {
+	ssrc           uint32
+	sequenceNumber uint16
+}
+
+// FeedbackAdapterStats reports feedbackHistory occupancy and eviction
+// counts, so callers can tune maxAge/maxEntries for their workload.
+type FeedbackAdapterStats struct {
+	// Occupancy is the number of sent packets currently held in history.
+	// Entries are not removed when feedback about them arrives, so this
+	// includes packets that have already been acknowledged.
+	Occupancy int
+
+	// EvictionsByAge is the number of entries discarded for exceeding
+	// maxAge, whether or not feedback about them had arrived.
+	EvictionsByAge int
+
+	// EvictionsByCap is the number of entries discarded for exceeding
+	// maxEntries, whether or not feedback about them had arrived.
+	EvictionsByCap int
+}
+
+// Stats reports the current occupancy and cumulative eviction counts of the
+// adapter's history.
+func (f *FeedbackAdapter) Stats() FeedbackAdapterStats {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.history.stats()
+}
+
 type feedbackHistory struct {
-	size      int
+	maxEntries int
+	maxAge     time.Duration
+
 	evictList *list.List
-	items     map[uint16]*list.Element
+	items     map[uint32]*list.Element
+	ssrcItems map[ssrcSequence]*list.Element
+
+	evictionsByAge int
+	evictionsByCap int
 }
 
-func newFeedbackHistory(size int) *feedbackHistory {
+func newFeedbackHistory(maxEntries int, maxAge time.Duration) *feedbackHistory {
 	return &feedbackHistory{
-		size:      size,
-		evictList: list.New(),
-		items:     make(map[uint16]*list.Element),
+		maxEntries: maxEntries,
+		maxAge:     maxAge,
+		evictList:  list.New(),
+		items:      make(map[uint32]*list.Element),
+		ssrcItems:  make(map[ssrcSequence]*list.Element),
 	}
 }
 
-func (f *feedbackHistory) get(key uint16) (Acknowledgment, bool) {
+func (f *feedbackHistory) get(key uint32, now time.Time) (Acknowledgment, bool) {
+	f.evictByAge(now)
 	ent, ok := f.items[key]
 	if ok {
 		if ack, ok := ent.Value.(Acknowledgment); ok {
@@ -170,27 +350,90 @@ func (f *feedbackHistory) get(key uint16) (Acknowledgment, bool) {
 	return Acknowledgment{}, false
 }
 
+func (f *feedbackHistory) getBySSRCSequence(ssrc uint32, sequenceNumber uint16, now time.Time) (Acknowledgment, bool) {
+	f.evictByAge(now)
+	ent, ok := f.ssrcItems[ssrcSequence{ssrc: ssrc, sequenceNumber: sequenceNumber}]
+	if ok {
+		if ack, ok := ent.Value.(Acknowledgment); ok {
+			return ack, true
+		}
+	}
+	return Acknowledgment{}, false
+}
+
 func (f *feedbackHistory) add(ack Acknowledgment) {
+	f.evictByAge(ack.Departure)
+
+	key := ssrcSequence{ssrc: ack.SSRC, sequenceNumber: ack.SequenceNumber}
+
 	// Check for existing
-	if ent, ok := f.items[ack.TLCC]; ok {
+	if ent, ok := f.ssrcItems[key]; ok {
+		if old, ok := ent.Value.(Acknowledgment); ok && old.HasTLCC && old.TLCC != ack.TLCC {
+			delete(f.items, old.TLCC)
+		}
 		f.evictList.MoveToFront(ent)
 		ent.Value = ack
+		if ack.HasTLCC {
+			f.items[ack.TLCC] = ent
+		}
 		return
 	}
+
 	// Add new
 	ent := f.evictList.PushFront(ack)
-	f.items[ack.TLCC] = ent
+	f.ssrcItems[key] = ent
+	if ack.HasTLCC {
+		f.items[ack.TLCC] = ent
+	}
+
 	// Evict if necessary
-	if f.evictList.Len() > f.size {
+	if f.maxEntries > 0 && f.evictList.Len() > f.maxEntries {
 		f.removeOldest()
+		f.evictionsByCap++
+	}
+}
+
+// evictByAge discards entries from the back of evictList (oldest first)
+// until the oldest remaining one is no older than maxAge relative to now.
+// It is a no-op when maxAge is 0.
+func (f *feedbackHistory) evictByAge(now time.Time) {
+	if f.maxAge <= 0 {
+		return
+	}
+	for {
+		ent := f.evictList.Back()
+		if ent == nil {
+			return
+		}
+		ack, ok := ent.Value.(Acknowledgment)
+		if !ok || now.Sub(ack.Departure) <= f.maxAge {
+			return
+		}
+		f.removeEntry(ent, ack)
+		f.evictionsByAge++
 	}
 }
 
 func (f *feedbackHistory) removeOldest() {
 	if ent := f.evictList.Back(); ent != nil {
-		f.evictList.Remove(ent)
 		if ack, ok := ent.Value.(Acknowledgment); ok {
-			delete(f.items, ack.TLCC)
+			f.removeEntry(ent, ack)
 		}
 	}
 }
+
+func (f *feedbackHistory) removeEntry(ent *list.Element, ack Acknowledgment) {
+	f.evictList.Remove(ent)
+	delete(f.ssrcItems, ssrcSequence{ssrc: ack.SSRC, sequenceNumber: ack.SequenceNumber})
+	if ack.HasTLCC {
+		delete(f.items, ack.TLCC)
+	}
+}
+
+func (f *feedbackHistory) stats() FeedbackAdapterStats {
+	return FeedbackAdapterStats{
+		Occupancy:      f.evictList.Len(),
+		EvictionsByAge: f.evictionsByAge,
+		EvictionsByCap: f.evictionsByCap,
+	}
+}
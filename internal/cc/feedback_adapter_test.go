@@ -0,0 +1,89 @@
+package cc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+const testTWCCExtensionID = 1
+
+func sendTestPacket(t *testing.T, f *FeedbackAdapter, ts time.Time, ssrc uint32, seq, tlcc uint16) {
+	t.Helper()
+
+	ext := rtp.TransportCCExtension{TransportSequence: tlcc}
+	payload, err := ext.Marshal()
+	if err != nil {
+		t.Fatalf("marshal TWCC extension: %v", err)
+	}
+
+	header := &rtp.Header{SSRC: ssrc, SequenceNumber: seq, Extension: true, ExtensionProfile: 0xBEDE}
+	if err := header.SetExtension(testTWCCExtensionID, payload); err != nil {
+		t.Fatalf("set TWCC extension: %v", err)
+	}
+
+	attrs := interceptor.Attributes{TwccExtensionAttributesKey: uint8(testTWCCExtensionID)}
+	if err := f.OnSent(ts, header, 100, attrs); err != nil {
+		t.Fatalf("OnSent: %v", err)
+	}
+}
+
+// TestFeedbackAdapterOnTransportCCFeedbackStraddlesRollover exercises a TWCC
+// feedback report whose packet range crosses the 16-bit TLCC rollover at
+// 0xffff, so each packet must resolve to the Acknowledgment it was actually
+// sent as rather than colliding with its pre-rollover counterpart.
+func TestFeedbackAdapterOnTransportCCFeedbackStraddlesRollover(t *testing.T) {
+	f := NewFeedbackAdapter()
+	base := time.Now()
+
+	sendTestPacket(t, f, base, 1, 100, 0xfffe)
+	sendTestPacket(t, f, base.Add(10*time.Millisecond), 1, 101, 0xffff)
+	sendTestPacket(t, f, base.Add(20*time.Millisecond), 1, 102, 0x0000)
+
+	feedback := &rtcp.TransportLayerCC{
+		BaseSequenceNumber: 0xfffe,
+		PacketStatusCount:  3,
+		ReferenceTime:      int32(base.UnixNano() / int64(64*time.Millisecond)),
+		PacketChunks: []rtcp.PacketStatusChunk{
+			&rtcp.RunLengthChunk{PacketStatusSymbol: rtcp.TypeTCCPacketReceivedSmallDelta, RunLength: 3},
+		},
+		RecvDeltas: []*rtcp.RecvDelta{
+			{Delta: 0},
+			{Delta: 10000},
+			{Delta: 10000},
+		},
+	}
+
+	acks, err := f.OnTransportCCFeedback(base.Add(30*time.Millisecond), feedback)
+	if err != nil {
+		t.Fatalf("OnTransportCCFeedback: %v", err)
+	}
+	if len(acks) != 3 {
+		t.Fatalf("got %d acks, want 3", len(acks))
+	}
+	for i, wantSeq := range []uint16{100, 101, 102} {
+		if acks[i].SequenceNumber != wantSeq {
+			t.Errorf("ack %d: got sequence %d, want %d", i, acks[i].SequenceNumber, wantSeq)
+		}
+	}
+}
+
+// TestFeedbackAdapterExtendTLCCRollover checks that extendTLCC keeps
+// producing a monotonically increasing 32-bit sequence across a 16-bit
+// wraparound instead of resetting back to a small value.
+func TestFeedbackAdapterExtendTLCCRollover(t *testing.T) {
+	f := NewFeedbackAdapter()
+
+	before := f.extendTLCC(0xfffe)
+	wrapped := f.extendTLCC(0x0001)
+
+	if wrapped <= before {
+		t.Fatalf("extendTLCC(0x0001) = %d, want > extendTLCC(0xfffe) = %d", wrapped, before)
+	}
+	if want := uint32(1<<16 + 1); wrapped != want {
+		t.Fatalf("extendTLCC(0x0001) after wrap = %d, want %d", wrapped, want)
+	}
+}
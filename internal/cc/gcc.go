@@ -0,0 +1,267 @@
+package cc
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+// overuseState is the output of the delay-based overuse detector.
+type overuseState int
+
+const (
+	overuseStateNormal overuseState = iota
+	overuseStateUnderuse
+	overuseStateOveruse
+)
+
+// rateControlState drives the AIMD bitrate update.
+type rateControlState int
+
+const (
+	rateControlHold rateControlState = iota
+	rateControlIncrease
+	rateControlDecrease
+)
+
+const (
+	gccDefaultMinBitrate     = 30_000
+	gccDefaultMaxBitrate     = 30_000_000
+	gccDefaultInitialBitrate = 300_000
+
+	overuseTimeThreshold = 10 * time.Millisecond
+	thresholdMin         = 6.0
+	thresholdMax         = 600.0
+	thresholdGainUp      = 0.01
+	thresholdGainDown    = 0.00018
+
+	rateIncreaseMultiplicative = 1.08
+	rateDecreaseFactor         = 0.85
+)
+
+// GCCOption configures a GCC.
+type GCCOption func(*GCC)
+
+// WithGCCInitialBitrate sets the starting target bitrate, in bits per
+// second.
+func WithGCCInitialBitrate(bps int) GCCOption {
+	return func(g *GCC) { g.bitrate = bps }
+}
+
+// WithGCCMinMaxBitrate bounds the bitrate GCC will converge to.
+func WithGCCMinMaxBitrate(minBitrate, maxBitrate int) GCCOption {
+	return func(g *GCC) { g.minBitrate, g.maxBitrate = minBitrate, maxBitrate }
+}
+
+// GCC is a delay-based CongestionController implementing the arrival-time
+// filter, trendline slope estimator, adaptive-threshold overuse detector and
+// AIMD rate control described in draft-ietf-rmcat-gcc. It is agnostic to
+// whether the Acknowledgments it receives were resolved from TWCC or
+// RFC 8888 feedback, since both already carry Departure/Arrival times.
+type GCC struct {
+	lock sync.Mutex
+
+	minBitrate, maxBitrate, bitrate int
+
+	lastDeparture, lastArrival time.Time
+	haveLast                   bool
+
+	trend trendlineEstimator
+
+	threshold    float64
+	lastOveruse  time.Time
+	state        overuseState
+	rcState      rateControlState
+	lastDecrease float64
+	haveDecrease bool
+
+	received *rateCounter
+}
+
+// NewGCC returns a GCC ready to receive Acknowledgments.
+func NewGCC(opts ...GCCOption) *GCC {
+	g := &GCC{
+		minBitrate: gccDefaultMinBitrate,
+		maxBitrate: gccDefaultMaxBitrate,
+		bitrate:    gccDefaultInitialBitrate,
+		threshold:  12.5,
+		received:   newRateCounter(time.Second),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// OnSent implements CongestionController. GCC only reacts to feedback, so
+// there is nothing to do on send.
+func (g *GCC) OnSent(Acknowledgment) {}
+
+// OnRTCP implements CongestionController. GCC only consumes Acknowledgments
+// already resolved by a FeedbackAdapter, not raw RTCP.
+func (g *GCC) OnRTCP(rtcp.Packet) {}
+
+// TargetBitrate implements CongestionController.
+func (g *GCC) TargetBitrate() int {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.bitrate
+}
+
+// OnAcknowledgments implements CongestionController.
+func (g *GCC) OnAcknowledgments(acks []Acknowledgment) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for _, ack := range acks {
+		if ack.Arrival.IsZero() {
+			// Packet loss is a signal for loss-based controllers, not the
+			// delay-based detector.
+			continue
+		}
+
+		g.received.add(ack.Size, ack.Arrival)
+
+		if g.haveLast {
+			interDeparture := ack.Departure.Sub(g.lastDeparture)
+			interArrival := ack.Arrival.Sub(g.lastArrival)
+			delayVariationMS := (interArrival - interDeparture).Seconds() * 1000
+
+			g.trend.add(delayVariationMS, ack.Arrival)
+			g.updateOveruseState(ack.Arrival)
+			g.updateRateControl(ack.Arrival)
+		}
+
+		g.lastDeparture = ack.Departure
+		g.lastArrival = ack.Arrival
+		g.haveLast = true
+	}
+}
+
+// updateOveruseState implements the adaptive-threshold overuse detector:
+// the trendline slope scaled by the number of deltas it was computed over
+// is compared against a threshold that itself adapts toward the observed
+// signal, so the detector stays sensitive across a wide range of channels.
+func (g *GCC) updateOveruseState(now time.Time) {
+	modifiedTrend := g.trend.slope * float64(len(g.trend.window))
+
+	switch {
+	case modifiedTrend > g.threshold:
+		if g.lastOveruse.IsZero() || now.Sub(g.lastOveruse) > overuseTimeThreshold {
+			g.state = overuseStateOveruse
+		}
+		g.lastOveruse = now
+	case modifiedTrend < -g.threshold:
+		g.state = overuseStateUnderuse
+	default:
+		g.state = overuseStateNormal
+	}
+
+	absTrend := math.Abs(modifiedTrend)
+	gain := thresholdGainDown
+	if absTrend > g.threshold {
+		gain = thresholdGainUp
+	}
+	g.threshold += gain * (absTrend - g.threshold)
+	g.threshold = math.Min(thresholdMax, math.Max(thresholdMin, g.threshold))
+}
+
+// updateRateControl applies the Hold/Increase/Decrease AIMD state machine
+// driven by the overuse detector.
+func (g *GCC) updateRateControl(now time.Time) {
+	switch g.state {
+	case overuseStateOveruse:
+		g.rcState = rateControlDecrease
+	case overuseStateUnderuse:
+		g.rcState = rateControlHold
+	case overuseStateNormal:
+		if g.rcState == rateControlDecrease {
+			g.rcState = rateControlHold
+		} else {
+			g.rcState = rateControlIncrease
+		}
+	}
+
+	received := g.received.bitrate(now)
+
+	switch g.rcState {
+	case rateControlIncrease:
+		if g.haveDecrease && float64(g.bitrate) < 1.5*g.lastDecrease {
+			// Still close to the last backoff: increase additively by
+			// roughly one packet's worth per update.
+			step := math.Max(float64(g.received.meanPacketSize()*8), 1000)
+			g.bitrate += int(step)
+		} else {
+			g.bitrate = int(float64(g.bitrate) * rateIncreaseMultiplicative)
+		}
+	case rateControlDecrease:
+		if received > 0 {
+			g.bitrate = int(rateDecreaseFactor * float64(received))
+			g.lastDecrease = float64(g.bitrate)
+			g.haveDecrease = true
+		}
+	case rateControlHold:
+	}
+
+	g.bitrate = int(math.Min(float64(g.maxBitrate), math.Max(float64(g.minBitrate), float64(g.bitrate))))
+}
+
+// trendlineWindowEntry is one sample of the trendline estimator's window.
+type trendlineWindowEntry struct {
+	arrival          time.Time
+	accumulatedDelay float64
+}
+
+const (
+	trendlineWindowSize    = 20
+	trendlineSmoothingCoef = 0.9
+)
+
+// trendlineEstimator smooths per-packet delay variation into a slope
+// (ms of queuing delay growth per ms of wall-clock time) via linear
+// regression over a sliding window, as in draft-ietf-rmcat-gcc.
+type trendlineEstimator struct {
+	window           []trendlineWindowEntry
+	accumulatedDelay float64
+	smoothedDelay    float64
+	slope            float64
+}
+
+func (t *trendlineEstimator) add(delayVariationMS float64, arrival time.Time) {
+	t.accumulatedDelay += delayVariationMS
+	t.smoothedDelay = trendlineSmoothingCoef*t.smoothedDelay + (1-trendlineSmoothingCoef)*t.accumulatedDelay
+
+	t.window = append(t.window, trendlineWindowEntry{arrival: arrival, accumulatedDelay: t.smoothedDelay})
+	if len(t.window) > trendlineWindowSize {
+		t.window = t.window[1:]
+	}
+
+	if len(t.window) < 2 {
+		t.slope = 0
+		return
+	}
+	t.slope = t.regressionSlope()
+}
+
+func (t *trendlineEstimator) regressionSlope() float64 {
+	first := t.window[0].arrival
+	var sumX, sumY, sumXY, sumXX float64
+	n := float64(len(t.window))
+
+	for _, e := range t.window {
+		x := e.arrival.Sub(first).Seconds() * 1000
+		y := e.accumulatedDelay
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
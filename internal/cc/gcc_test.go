@@ -0,0 +1,64 @@
+package cc
+
+import (
+	"testing"
+	"time"
+)
+
+// sendGCCAcks feeds g a run of n Acknowledgments spaced packetInterval apart
+// at departure time, with jitterPerPacket added to each one's arrival gap
+// (so a constant jitterPerPacket produces a steadily growing one-way delay).
+func sendGCCAcks(g *GCC, start time.Time, n int, packetInterval, jitterPerPacket time.Duration) {
+	departure := start
+	arrival := start
+	for i := 0; i < n; i++ {
+		g.OnAcknowledgments([]Acknowledgment{{
+			Size:      1200,
+			Departure: departure,
+			Arrival:   arrival,
+		}})
+		departure = departure.Add(packetInterval)
+		arrival = arrival.Add(packetInterval + jitterPerPacket)
+	}
+}
+
+func TestGCCIncreasesRateOnStableDelay(t *testing.T) {
+	g := NewGCC(WithGCCInitialBitrate(300_000))
+	start := time.Now()
+
+	sendGCCAcks(g, start, 200, 10*time.Millisecond, 0)
+
+	if got := g.TargetBitrate(); got <= 300_000 {
+		t.Errorf("TargetBitrate() = %d, want > 300000 after a run with no delay growth", got)
+	}
+}
+
+func TestGCCDecreasesRateOnGrowingDelay(t *testing.T) {
+	// The decrease branch pulls the target down toward a fraction of the
+	// observed receive throughput (~160kbps: 1200-byte packets arriving 60ms
+	// apart),
+	// so the initial bitrate must start above that for a decrease to show up
+	// as a drop rather than a jump toward it from below. The per-packet
+	// delay growth must also be steep enough, relative to the inter-packet
+	// time it's spread over, to move the trendline slope past the overuse
+	// threshold at all.
+	const initialBitrate = 5_000_000
+	g := NewGCC(WithGCCInitialBitrate(initialBitrate))
+	start := time.Now()
+
+	sendGCCAcks(g, start, 60, 10*time.Millisecond, 50*time.Millisecond)
+
+	if got := g.TargetBitrate(); got >= initialBitrate {
+		t.Errorf("TargetBitrate() = %d, want < %d after a run with steadily growing delay", got, initialBitrate)
+	}
+}
+
+func TestGCCIgnoresLostPackets(t *testing.T) {
+	g := NewGCC(WithGCCInitialBitrate(300_000))
+
+	g.OnAcknowledgments([]Acknowledgment{{Size: 1200}}) // zero Arrival: reported lost
+
+	if got := g.TargetBitrate(); got != 300_000 {
+		t.Errorf("TargetBitrate() = %d, want unchanged 300000 after only a lost packet", got)
+	}
+}
@@ -0,0 +1,102 @@
+package cc
+
+import (
+	"sync"
+
+	"github.com/pion/rtcp"
+)
+
+const (
+	lossBasedDefaultMinBitrate     = 30_000
+	lossBasedDefaultMaxBitrate     = 30_000_000
+	lossBasedDefaultInitialBitrate = 300_000
+
+	lossBasedHighWaterMark = 0.1
+	lossBasedLowWaterMark  = 0.02
+	lossBasedBackoffGain   = 0.5
+	lossBasedProbeGain     = 1.05
+)
+
+// LossBasedOption configures a LossBased controller.
+type LossBasedOption func(*LossBased)
+
+// WithLossBasedInitialBitrate sets the starting target bitrate, in bits per
+// second.
+func WithLossBasedInitialBitrate(bps int) LossBasedOption {
+	return func(l *LossBased) { l.bitrate = bps }
+}
+
+// WithLossBasedMinMaxBitrate bounds the bitrate LossBased will converge to.
+func WithLossBasedMinMaxBitrate(minBitrate, maxBitrate int) LossBasedOption {
+	return func(l *LossBased) { l.minBitrate, l.maxBitrate = minBitrate, maxBitrate }
+}
+
+// LossBased is a CongestionController that adjusts its target bitrate from
+// the fraction of packets reported lost in incoming feedback: it backs off
+// multiplicatively once loss crosses a high-water mark, probes upward
+// multiplicatively by lossBasedProbeGain once loss is below a low-water mark,
+// and holds its bitrate when loss lies between the two marks. It
+// reads loss directly off Acknowledgment.Arrival, so it works unmodified
+// whether the feedback driving it is TWCC or RFC 8888.
+type LossBased struct {
+	lock sync.Mutex
+
+	minBitrate, maxBitrate, bitrate int
+}
+
+// NewLossBased returns a LossBased ready to receive Acknowledgments.
+func NewLossBased(opts ...LossBasedOption) *LossBased {
+	l := &LossBased{
+		minBitrate: lossBasedDefaultMinBitrate,
+		maxBitrate: lossBasedDefaultMaxBitrate,
+		bitrate:    lossBasedDefaultInitialBitrate,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// OnSent implements CongestionController.
+func (l *LossBased) OnSent(Acknowledgment) {}
+
+// OnRTCP implements CongestionController.
+func (l *LossBased) OnRTCP(rtcp.Packet) {}
+
+// TargetBitrate implements CongestionController.
+func (l *LossBased) TargetBitrate() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.bitrate
+}
+
+// OnAcknowledgments implements CongestionController.
+func (l *LossBased) OnAcknowledgments(acks []Acknowledgment) {
+	if len(acks) == 0 {
+		return
+	}
+
+	lost := 0
+	for _, ack := range acks {
+		if ack.Arrival.IsZero() {
+			lost++
+		}
+	}
+	fractionLost := float64(lost) / float64(len(acks))
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	switch {
+	case fractionLost > lossBasedHighWaterMark:
+		l.bitrate = int(float64(l.bitrate) * (1 - lossBasedBackoffGain*fractionLost))
+	case fractionLost < lossBasedLowWaterMark:
+		l.bitrate = int(float64(l.bitrate) * lossBasedProbeGain)
+	}
+
+	if l.bitrate < l.minBitrate {
+		l.bitrate = l.minBitrate
+	} else if l.bitrate > l.maxBitrate {
+		l.bitrate = l.maxBitrate
+	}
+}
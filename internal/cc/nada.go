@@ -0,0 +1,134 @@
+package cc
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+const (
+	nadaDefaultMinBitrate     = 30_000
+	nadaDefaultMaxBitrate     = 30_000_000
+	nadaDefaultInitialBitrate = 300_000
+
+	nadaQueuingDelayTarget = 50 * time.Millisecond  // QTH: accepted region boundary
+	nadaQueuingDelayMax    = 400 * time.Millisecond // QMAX: full backoff point
+	nadaLossTolerance      = 0.02                   // PLR above which NADA backs off
+	nadaRampUpGain         = 0.05                   // per-update fraction of headroom gained
+)
+
+// NADAOption configures a NADA.
+type NADAOption func(*NADA)
+
+// WithNADAInitialBitrate sets the starting target bitrate, in bits per
+// second.
+func WithNADAInitialBitrate(bps int) NADAOption {
+	return func(n *NADA) { n.bitrate, n.referenceRate = bps, float64(bps) }
+}
+
+// WithNADAMinMaxBitrate bounds the bitrate NADA will converge to.
+func WithNADAMinMaxBitrate(minBitrate, maxBitrate int) NADAOption {
+	return func(n *NADA) { n.minBitrate, n.maxBitrate = minBitrate, maxBitrate }
+}
+
+// NADA is a sender-side CongestionController approximating the rate update
+// of RFC 8698 (NADA): an estimated end-to-end queuing delay and the loss
+// ratio reported by feedback are mapped onto a reference rate that ramps up
+// gently inside an accepted region and backs off multiplicatively once
+// either exceeds its threshold. It estimates one-way queuing delay from half
+// of Acknowledgment.RTT above the observed minimum, rather than from NADA's
+// explicit receiver-side delay signaling, since neither TWCC nor RFC 8888
+// carry a one-way delay field; this is a reduced approximation of the full
+// scheme, not a conformant implementation of its reference/receiver split.
+type NADA struct {
+	lock sync.Mutex
+
+	minBitrate, maxBitrate, bitrate int
+	referenceRate                   float64
+
+	minRTT  time.Duration
+	haveRTT bool
+}
+
+// NewNADA returns a NADA ready to receive Acknowledgments.
+func NewNADA(opts ...NADAOption) *NADA {
+	n := &NADA{
+		minBitrate:    nadaDefaultMinBitrate,
+		maxBitrate:    nadaDefaultMaxBitrate,
+		bitrate:       nadaDefaultInitialBitrate,
+		referenceRate: nadaDefaultInitialBitrate,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// OnSent implements CongestionController.
+func (n *NADA) OnSent(Acknowledgment) {}
+
+// OnRTCP implements CongestionController.
+func (n *NADA) OnRTCP(rtcp.Packet) {}
+
+// TargetBitrate implements CongestionController.
+func (n *NADA) TargetBitrate() int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.bitrate
+}
+
+// OnAcknowledgments implements CongestionController.
+func (n *NADA) OnAcknowledgments(acks []Acknowledgment) {
+	if len(acks) == 0 {
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	lost := 0
+	var rttSum time.Duration
+	received := 0
+	for _, ack := range acks {
+		if ack.Arrival.IsZero() {
+			lost++
+			continue
+		}
+		received++
+		rttSum += ack.RTT
+		if !n.haveRTT || ack.RTT < n.minRTT {
+			n.minRTT = ack.RTT
+			n.haveRTT = true
+		}
+	}
+	if received == 0 || !n.haveRTT {
+		return
+	}
+
+	avgRTT := rttSum / time.Duration(received)
+	queuingDelay := avgRTT/2 - n.minRTT/2
+	if queuingDelay < 0 {
+		queuingDelay = 0
+	}
+	lossRatio := float64(lost) / float64(len(acks))
+
+	n.referenceRate = warp(n.referenceRate, queuingDelay, lossRatio)
+	n.bitrate = int(math.Min(float64(n.maxBitrate), math.Max(float64(n.minBitrate), n.referenceRate)))
+}
+
+// warp implements the accelerated-ramp-up / multiplicative-backoff rate
+// update: inside the accepted delay/loss region the rate is nudged upward
+// proportional to its remaining headroom to the delay target; once either
+// signal exceeds its threshold the rate backs off proportional to how far
+// over it is.
+func warp(rate float64, queuingDelay time.Duration, lossRatio float64) float64 {
+	if queuingDelay > nadaQueuingDelayMax || lossRatio > nadaLossTolerance {
+		severity := math.Min(1, lossRatio+float64(queuingDelay)/float64(nadaQueuingDelayMax))
+		return rate * (1 - 0.5*severity)
+	}
+
+	headroom := 1 - float64(queuingDelay)/float64(nadaQueuingDelayTarget)
+	return rate + nadaRampUpGain*headroom*rate
+}
@@ -0,0 +1,96 @@
+package cc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// packetToPace is a single RTP packet queued by Pacer.
+type packetToPace struct {
+	header  *rtp.Header
+	payload []byte
+	size    int
+}
+
+// Pacer smooths outgoing writes to roughly match a CongestionController's
+// TargetBitrate instead of writing packets as fast as the application
+// produces them. It only consults TargetBitrate(), so it works unchanged
+// whether the controller behind it is driven by TWCC or RFC 8888 feedback.
+type Pacer struct {
+	controller CongestionController
+	write      func(header *rtp.Header, payload []byte) error
+	interval   time.Duration
+
+	lock  sync.Mutex
+	queue []packetToPace
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewPacer returns a Pacer that, every interval, writes queued packets until
+// the byte budget implied by controller.TargetBitrate() for that interval is
+// exhausted.
+func NewPacer(controller CongestionController, write func(header *rtp.Header, payload []byte) error, interval time.Duration) *Pacer {
+	p := &Pacer{
+		controller: controller,
+		write:      write,
+		interval:   interval,
+		done:       make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Write queues an RTP packet to be sent on a future pacing tick.
+func (p *Pacer) Write(header *rtp.Header, payload []byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.queue = append(p.queue, packetToPace{header: header, payload: payload, size: header.MarshalSize() + len(payload)})
+}
+
+// Close stops the pacing loop. Queued packets that have not yet been written
+// are dropped. Close may be called more than once, including concurrently.
+func (p *Pacer) Close() error {
+	p.closeOnce.Do(func() { close(p.done) })
+	return nil
+}
+
+func (p *Pacer) run() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.tick()
+		}
+	}
+}
+
+func (p *Pacer) tick() {
+	budget := int(float64(p.controller.TargetBitrate()) * p.interval.Seconds() / 8)
+	if budget <= 0 {
+		budget = 1
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	sent := 0
+	for len(p.queue) > 0 {
+		next := p.queue[0]
+		if sent > 0 && sent+next.size > budget {
+			break
+		}
+		if err := p.write(next.header, next.payload); err != nil {
+			break
+		}
+		sent += next.size
+		p.queue = p.queue[1:]
+	}
+}
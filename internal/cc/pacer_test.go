@@ -0,0 +1,81 @@
+package cc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// fakeController is a minimal CongestionController stub for Pacer tests; it
+// reports a fixed TargetBitrate and ignores everything else.
+type fakeController struct {
+	bitrate int
+}
+
+func (f *fakeController) OnAcknowledgments([]Acknowledgment) {}
+func (f *fakeController) OnSent(Acknowledgment)              {}
+func (f *fakeController) TargetBitrate() int                 { return f.bitrate }
+func (f *fakeController) OnRTCP(rtcp.Packet)                 {}
+
+func TestPacerRespectsTargetBitrateBudget(t *testing.T) {
+	controller := &fakeController{bitrate: 8000} // 1000 bytes/sec
+	const interval = 100 * time.Millisecond      // 100 bytes/tick budget
+
+	var mu sync.Mutex
+	var written []int
+
+	write := func(header *rtp.Header, payload []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		written = append(written, header.MarshalSize()+len(payload))
+		return nil
+	}
+
+	p := NewPacer(controller, write, interval)
+	defer p.Close()
+
+	header := &rtp.Header{}
+	for i := 0; i < 5; i++ {
+		p.Write(header, make([]byte, 50)) // 62 bytes each, over the 100-byte/tick budget in pairs
+	}
+
+	time.Sleep(3 * interval)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(written) == 0 {
+		t.Fatalf("expected the pacer to have written at least one packet")
+	}
+	if len(written) >= 5 {
+		t.Errorf("got %d of 5 queued packets written within 3 budget ticks, want fewer than all 5", len(written))
+	}
+}
+
+func TestPacerCloseStopsDelivery(t *testing.T) {
+	controller := &fakeController{bitrate: 8_000_000}
+
+	var mu sync.Mutex
+	written := 0
+	write := func(*rtp.Header, []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		written++
+		return nil
+	}
+
+	p := NewPacer(controller, write, 10*time.Millisecond)
+	p.Close()
+	p.Close() // must not panic
+
+	p.Write(&rtp.Header{}, nil)
+	time.Sleep(30 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if written != 0 {
+		t.Errorf("got %d packets written after Close, want 0", written)
+	}
+}
@@ -0,0 +1,67 @@
+package cc
+
+import "time"
+
+// rateSample is one accounted packet for rateCounter.
+type rateSample struct {
+	at   time.Time
+	size int
+}
+
+// rateCounter estimates a bitrate in bits per second over a sliding time
+// window, used by the bundled CongestionController implementations to track
+// the rate actually being received.
+type rateCounter struct {
+	window  time.Duration
+	samples []rateSample
+}
+
+func newRateCounter(window time.Duration) *rateCounter {
+	return &rateCounter{window: window}
+}
+
+func (r *rateCounter) add(size int, at time.Time) {
+	r.samples = append(r.samples, rateSample{at: at, size: size})
+	r.evict(at)
+}
+
+func (r *rateCounter) evict(now time.Time) {
+	cutoff := now.Add(-r.window)
+	i := 0
+	for i < len(r.samples) && r.samples[i].at.Before(cutoff) {
+		i++
+	}
+	r.samples = r.samples[i:]
+}
+
+// bitrate returns the estimated bitrate in bits per second, or 0 if no
+// samples have been recorded within the window.
+func (r *rateCounter) bitrate(now time.Time) int {
+	r.evict(now)
+	if len(r.samples) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, s := range r.samples {
+		total += s.size
+	}
+
+	elapsed := now.Sub(r.samples[0].at).Seconds()
+	if elapsed <= 0 {
+		elapsed = r.window.Seconds()
+	}
+	return int(float64(total*8) / elapsed)
+}
+
+// meanPacketSize returns the average sample size, in bytes, or 0 if empty.
+func (r *rateCounter) meanPacketSize() int {
+	if len(r.samples) == 0 {
+		return 0
+	}
+	total := 0
+	for _, s := range r.samples {
+		total += s.size
+	}
+	return total / len(r.samples)
+}
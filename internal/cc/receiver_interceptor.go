@@ -0,0 +1,192 @@
+package cc
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+// twccHeaderExtensionURI is the header extension a ReceiverInterceptor
+// installs itself against at bind time, so callers get end-to-end
+// congestion feedback without wiring the extension up by hand.
+const twccHeaderExtensionURI = "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"
+
+const (
+	defaultReceiverFeedbackInterval = 100 * time.Millisecond
+	minReceiverFeedbackInterval     = 50 * time.Millisecond
+	maxReceiverFeedbackInterval     = 250 * time.Millisecond
+
+	// feedbackBandwidthFraction bounds how much of the receive bitrate
+	// feedback reporting itself is allowed to consume, the same tradeoff
+	// RTCP bandwidth fraction limits make.
+	feedbackBandwidthFraction = 0.05
+)
+
+// receiverFeedbackMode selects which feedback format a ReceiverInterceptor
+// emits; both read from the same Recorder.
+type receiverFeedbackMode int
+
+const (
+	receiverFeedbackTWCC receiverFeedbackMode = iota
+	receiverFeedbackRFC8888
+)
+
+// ReceiverInterceptorOption configures a ReceiverInterceptor.
+type ReceiverInterceptorOption func(*ReceiverInterceptor)
+
+// WithReceiverFeedbackInterval sets the starting feedback reporting
+// interval. It is still adapted to the observed receive bitrate afterward,
+// within [minReceiverFeedbackInterval, maxReceiverFeedbackInterval].
+func WithReceiverFeedbackInterval(interval time.Duration) ReceiverInterceptorOption {
+	return func(r *ReceiverInterceptor) { r.interval = interval }
+}
+
+// WithRFC8888Feedback switches the ReceiverInterceptor from emitting TWCC
+// (rtcp.TransportLayerCC) to emitting RFC 8888 (rtcp.CCFeedbackReport).
+func WithRFC8888Feedback() ReceiverInterceptorOption {
+	return func(r *ReceiverInterceptor) { r.mode = receiverFeedbackRFC8888 }
+}
+
+// ReceiverInterceptor is the receiver-side counterpart to Interceptor: it
+// records incoming RTP via a Recorder and periodically emits TWCC or
+// RFC 8888 feedback built from it, so a sender running Interceptor gets
+// congestion feedback without extra wiring.
+type ReceiverInterceptor struct {
+	interceptor.NoOp
+
+	recorder *Recorder
+	interval time.Duration
+	mode     receiverFeedbackMode
+
+	closeOnce sync.Once
+	close     chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewReceiverInterceptor returns a ReceiverInterceptor emitting TWCC
+// feedback, first after defaultReceiverFeedbackInterval and then at an
+// interval adapted to the receive bitrate, unless overridden by opts.
+func NewReceiverInterceptor(opts ...ReceiverInterceptorOption) (*ReceiverInterceptor, error) {
+	r := &ReceiverInterceptor{
+		recorder: NewRecorder(rand.Uint32()), //nolint:gosec // not a security-sensitive use of randomness
+		interval: defaultReceiverFeedbackInterval,
+		close:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// BindRemoteStream records every incoming RTP packet, tagging it with its
+// TWCC transport sequence number when info advertises the TWCC header
+// extension.
+func (r *ReceiverInterceptor) BindRemoteStream(info *interceptor.StreamInfo, reader interceptor.RTPReader) interceptor.RTPReader {
+	var extensionID uint8
+	for _, e := range info.RTPHeaderExtensions {
+		if e.URI == twccHeaderExtensionURI {
+			extensionID = uint8(e.ID)
+			break
+		}
+	}
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+
+		var header rtp.Header
+		if _, unmarshalErr := header.Unmarshal(b[:n]); unmarshalErr != nil {
+			return n, attr, nil
+		}
+
+		var transportSequence uint16
+		haveTransportSequence := false
+		if extensionID != 0 {
+			if payload := header.GetExtension(extensionID); payload != nil {
+				var tccExt rtp.TransportCCExtension
+				if tccExt.Unmarshal(payload) == nil {
+					transportSequence = tccExt.TransportSequence
+					haveTransportSequence = true
+				}
+			}
+		}
+
+		r.recorder.Record(header.SSRC, header.SequenceNumber, n, time.Now(), transportSequence, haveTransportSequence)
+
+		return n, attr, nil
+	})
+}
+
+// BindRTCPWriter starts the periodic feedback emission loop, writing
+// through writer, and returns writer unmodified.
+func (r *ReceiverInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	r.wg.Add(1)
+	go r.run(writer)
+	return writer
+}
+
+// Close stops the feedback emission loop. Close may be called more than
+// once, including concurrently.
+func (r *ReceiverInterceptor) Close() error {
+	r.closeOnce.Do(func() { close(r.close) })
+	r.wg.Wait()
+	return nil
+}
+
+func (r *ReceiverInterceptor) run(writer interceptor.RTCPWriter) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var fbPktCount uint8
+	for {
+		select {
+		case <-r.close:
+			return
+		case now := <-ticker.C:
+			switch r.mode {
+			case receiverFeedbackTWCC:
+				if report, ok := r.recorder.BuildTransportCCFeedback(fbPktCount); ok {
+					_, _ = writer.Write([]rtcp.Packet{report}, nil)
+					fbPktCount++
+				}
+			case receiverFeedbackRFC8888:
+				if report, ok := r.recorder.BuildRFC8888(now); ok {
+					_, _ = writer.Write([]rtcp.Packet{report}, nil)
+				}
+			}
+
+			ticker.Reset(r.adaptiveInterval(now))
+		}
+	}
+}
+
+// adaptiveInterval scales the reporting interval to the observed receive
+// bitrate, so feedback overhead stays within feedbackBandwidthFraction of
+// the channel regardless of whether the session is running at a few
+// kilobits or tens of megabits per second.
+func (r *ReceiverInterceptor) adaptiveInterval(now time.Time) time.Duration {
+	bitrate := r.recorder.ReceivedBitrate(now)
+	if bitrate <= 0 {
+		return r.interval
+	}
+
+	const approxReportBits = 8 * (20 + 2*32) // header + ~32 packets' worth of chunks/deltas
+	budgetBitsPerSecond := float64(bitrate) * feedbackBandwidthFraction
+
+	interval := time.Duration(float64(approxReportBits) / budgetBitsPerSecond * float64(time.Second))
+	if interval < minReceiverFeedbackInterval {
+		return minReceiverFeedbackInterval
+	}
+	if interval > maxReceiverFeedbackInterval {
+		return maxReceiverFeedbackInterval
+	}
+	return interval
+}
@@ -0,0 +1,375 @@
+package cc
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+const (
+	twccReferenceTimeUnit       = 64 * time.Millisecond
+	twccSmallDeltaUnit          = 250 * time.Microsecond
+	twccSmallDeltaMax           = twccSmallDeltaUnit * 255
+	twccMaxRunLength            = 0x1fff // 13-bit PacketChunk run length field
+	twccMinUniformRunLength     = 3      // below this, pack into a status-vector chunk instead
+	statusVectorSymbolsPerChunk = 7      // 2-bit symbol size: 14 symbol bits / 2
+
+	rfc8888TickUnit = time.Second / 1024
+)
+
+// twccSymbol classifies one recorded (or missing) packet for TWCC encoding,
+// before it's translated to the wire rtcp.PacketStatusSymbol.
+type twccSymbol int
+
+const (
+	twccSymbolNotReceived twccSymbol = iota
+	twccSymbolSmallDelta
+	twccSymbolLargeDelta
+)
+
+// packetStatus is one packet recorded for TWCC, keyed by its extended
+// transport-wide sequence number.
+type packetStatus struct {
+	arrival time.Time
+}
+
+// rfc8888Packet is one packet recorded for RFC 8888, keyed by SSRC and
+// carrying its extended (rollover-aware) sequence number.
+type rfc8888Packet struct {
+	sequenceNumber uint32
+	arrival        time.Time
+}
+
+// rfc8888SeqState tracks rollovers of one SSRC's 16-bit RTP sequence number
+// for BuildRFC8888, analogous to the TWCC cycle-counting fields on Recorder
+// but kept per SSRC since RFC 8888 groups packets by media SSRC rather than
+// a single transport-wide stream.
+type rfc8888SeqState struct {
+	cycles   uint32
+	last     uint16
+	haveLast bool
+}
+
+// Recorder is the receiver-side counterpart to FeedbackAdapter: it observes
+// incoming RTP packets and builds outgoing TWCC (rtcp.TransportLayerCC)
+// and/or RFC 8888 (rtcp.CCFeedbackReport) feedback from the same recorded
+// arrival history, so a ReceiverInterceptor can serve whichever format the
+// sender understands without recording packets twice.
+type Recorder struct {
+	lock sync.Mutex
+
+	senderSSRC uint32
+	received   *rateCounter
+
+	// TWCC state, keyed by the extended (rollover-aware) transport-wide
+	// sequence number.
+	twcc            map[uint32]packetStatus
+	twccCycles      uint32
+	lastTWCCSeq     uint16
+	haveLastTWCCSeq bool
+	twccReported    uint32
+	twccMax         uint32
+	haveTWCC        bool
+
+	// RFC 8888 state, keyed by media SSRC; each slice holds packets
+	// observed since the last report for that SSRC.
+	rfc8888    map[uint32][]rfc8888Packet
+	rfc8888Seq map[uint32]*rfc8888SeqState
+}
+
+// NewRecorder returns a Recorder that identifies itself as senderSSRC in
+// feedback it builds.
+func NewRecorder(senderSSRC uint32) *Recorder {
+	return &Recorder{
+		senderSSRC: senderSSRC,
+		received:   newRateCounter(time.Second),
+		twcc:       map[uint32]packetStatus{},
+		rfc8888:    map[uint32][]rfc8888Packet{},
+		rfc8888Seq: map[uint32]*rfc8888SeqState{},
+	}
+}
+
+// Record records an incoming RTP packet. transportSequenceNumber is only
+// meaningful when hasTransportSequenceNumber is true, i.e. the packet
+// carried the TWCC header extension; RFC 8888 reporting only needs
+// ssrc/sequenceNumber/arrival.
+func (r *Recorder) Record(ssrc uint32, sequenceNumber uint16, size int, arrival time.Time, transportSequenceNumber uint16, hasTransportSequenceNumber bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.received.add(size, arrival)
+	extendedSeq := r.extendRFC8888Seq(ssrc, sequenceNumber)
+	r.rfc8888[ssrc] = append(r.rfc8888[ssrc], rfc8888Packet{sequenceNumber: extendedSeq, arrival: arrival})
+
+	if !hasTransportSequenceNumber {
+		return
+	}
+
+	extended := r.extendTWCCSeq(transportSequenceNumber)
+	r.twcc[extended] = packetStatus{arrival: arrival}
+	if !r.haveTWCC || extended < r.twccReported {
+		r.twccReported = extended
+	}
+	if !r.haveTWCC || extended > r.twccMax {
+		r.twccMax = extended
+	}
+	r.haveTWCC = true
+}
+
+// ReceivedBitrate returns the recently observed receive bitrate, in bits
+// per second, used to adapt the feedback reporting interval.
+func (r *Recorder) ReceivedBitrate(now time.Time) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.received.bitrate(now)
+}
+
+// extendTWCCSeq tracks rollovers of the 16-bit TWCC transport sequence
+// number the same way FeedbackAdapter.extendTLCC does on the sender side.
+func (r *Recorder) extendTWCCSeq(sequenceNumber uint16) uint32 {
+	if r.haveLastTWCCSeq && sequenceNumber < 0x0fff && r.lastTWCCSeq > 0xf000 {
+		r.twccCycles += 1 << 16
+	}
+	r.lastTWCCSeq = sequenceNumber
+	r.haveLastTWCCSeq = true
+	return r.twccCycles + uint32(sequenceNumber)
+}
+
+// extendRFC8888Seq tracks rollovers of ssrc's 16-bit RTP sequence number the
+// same way extendTWCCSeq does for the transport-wide sequence, so a report
+// that spans a wraparound still sorts and diffs correctly.
+func (r *Recorder) extendRFC8888Seq(ssrc uint32, sequenceNumber uint16) uint32 {
+	state, ok := r.rfc8888Seq[ssrc]
+	if !ok {
+		state = &rfc8888SeqState{}
+		r.rfc8888Seq[ssrc] = state
+	}
+	if state.haveLast && sequenceNumber < 0x0fff && state.last > 0xf000 {
+		state.cycles += 1 << 16
+	}
+	state.last = sequenceNumber
+	state.haveLast = true
+	return state.cycles + uint32(sequenceNumber)
+}
+
+// BuildTransportCCFeedback builds a TWCC report covering every packet
+// observed since the last call (or since construction), then clears that
+// range so the next report starts where this one left off. It returns
+// false if no TWCC-tagged packet has been recorded yet.
+func (r *Recorder) BuildTransportCCFeedback(fbPktCount uint8) (*rtcp.TransportLayerCC, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.haveTWCC || r.twccReported > r.twccMax {
+		return nil, false
+	}
+
+	base := r.twccReported
+	count := r.twccMax - base + 1
+
+	refTime := r.twcc[r.firstRecordedFrom(base)].arrival.Truncate(twccReferenceTimeUnit)
+	cursor := refTime
+
+	slots := make([]twccSlot, 0, count)
+	for seq := base; seq <= r.twccMax; seq++ {
+		status, ok := r.twcc[seq]
+		if !ok {
+			slots = append(slots, twccSlot{symbol: twccSymbolNotReceived})
+			continue
+		}
+
+		deltaMicros := status.arrival.Sub(cursor).Microseconds()
+		cursor = status.arrival
+
+		symbol := twccSymbolSmallDelta
+		if deltaMicros < 0 || time.Duration(deltaMicros)*time.Microsecond > twccSmallDeltaMax {
+			symbol = twccSymbolLargeDelta
+		}
+		slots = append(slots, twccSlot{symbol: symbol, deltaMicros: deltaMicros})
+	}
+
+	chunks, deltas := encodeTWCCSlots(slots)
+
+	for seq := base; seq <= r.twccMax; seq++ {
+		delete(r.twcc, seq)
+	}
+	r.twccReported = r.twccMax + 1
+
+	return &rtcp.TransportLayerCC{
+		SenderSSRC:         r.senderSSRC,
+		MediaSSRC:          0, // transport-wide: not tied to a single media SSRC
+		BaseSequenceNumber: uint16(base),
+		PacketStatusCount:  uint16(count),
+		ReferenceTime:      int32(refTime.UnixNano() / int64(twccReferenceTimeUnit)),
+		FbPktCount:         fbPktCount,
+		PacketChunks:       chunks,
+		RecvDeltas:         deltas,
+	}, true
+}
+
+// firstRecordedFrom returns the first extended sequence number at or after
+// from that was actually recorded, used to pick a sane reference time when
+// the report's base sequence itself was never received.
+func (r *Recorder) firstRecordedFrom(from uint32) uint32 {
+	for seq := from; seq <= r.twccMax; seq++ {
+		if _, ok := r.twcc[seq]; ok {
+			return seq
+		}
+	}
+	return from
+}
+
+// twccSlot is one packet's classification within a pending TWCC report.
+type twccSlot struct {
+	symbol      twccSymbol
+	deltaMicros int64
+}
+
+// encodeTWCCSlots packs a sequence of twccSlots into PacketChunks, using a
+// run-length chunk for uniform runs of at least twccMinUniformRunLength
+// slots and a status-vector chunk for shorter, mixed runs in between.
+func encodeTWCCSlots(slots []twccSlot) ([]rtcp.PacketStatusChunk, []*rtcp.RecvDelta) {
+	chunks := []rtcp.PacketStatusChunk{}
+	deltas := []*rtcp.RecvDelta{}
+
+	appendDelta := func(slot twccSlot) {
+		if slot.symbol != twccSymbolNotReceived {
+			deltas = append(deltas, &rtcp.RecvDelta{Type: toRTCPSymbol(slot.symbol), Delta: slot.deltaMicros})
+		}
+	}
+
+	i := 0
+	for i < len(slots) {
+		runLength := uniformRunLength(slots, i)
+
+		if runLength >= twccMinUniformRunLength || runLength == len(slots)-i {
+			chunks = append(chunks, &rtcp.RunLengthChunk{
+				PacketStatusSymbol: toRTCPSymbol(slots[i].symbol),
+				RunLength:          uint16(runLength),
+			})
+			for k := 0; k < runLength; k++ {
+				appendDelta(slots[i+k])
+			}
+			i += runLength
+			continue
+		}
+
+		vectorLength := vectorRunLength(slots, i)
+		symbolList := make([]rtcp.PacketStatusSymbol, vectorLength)
+		for k := 0; k < vectorLength; k++ {
+			symbolList[k] = toRTCPSymbol(slots[i+k].symbol)
+			appendDelta(slots[i+k])
+		}
+		chunks = append(chunks, &rtcp.StatusVectorChunk{SymbolSize: rtcp.TypeTCCSymbolSizeTwoBit, SymbolList: symbolList})
+		i += vectorLength
+	}
+
+	return chunks, deltas
+}
+
+// uniformRunLength returns how many consecutive slots starting at i share
+// the same symbol, capped at twccMaxRunLength.
+func uniformRunLength(slots []twccSlot, i int) int {
+	n := 1
+	for i+n < len(slots) && slots[i+n].symbol == slots[i].symbol && n < twccMaxRunLength {
+		n++
+	}
+	return n
+}
+
+// vectorRunLength returns how many slots starting at i should be packed
+// into a single status-vector chunk: up to statusVectorSymbolsPerChunk,
+// stopping early once a long uniform run (better served by a run-length
+// chunk) begins.
+func vectorRunLength(slots []twccSlot, i int) int {
+	n := 0
+	for n < statusVectorSymbolsPerChunk && i+n < len(slots) {
+		if uniformRunLength(slots, i+n) >= twccMinUniformRunLength {
+			break
+		}
+		n++
+	}
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+func toRTCPSymbol(symbol twccSymbol) rtcp.PacketStatusSymbol {
+	switch symbol {
+	case twccSymbolSmallDelta:
+		return rtcp.TypeTCCPacketReceivedSmallDelta
+	case twccSymbolLargeDelta:
+		return rtcp.TypeTCCPacketReceivedLargeDelta
+	default:
+		return rtcp.TypeTCCPacketNotReceived
+	}
+}
+
+// BuildRFC8888 builds one RFC 8888 report covering every packet observed
+// since the last call, grouped per media SSRC, then clears that SSRC's
+// pending packets. now is used both as the report timestamp and as the
+// reference instant arrival time offsets are computed against. It returns
+// false if nothing has been recorded since the last call.
+func (r *Recorder) BuildRFC8888(now time.Time) (*rtcp.CCFeedbackReport, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.rfc8888) == 0 {
+		return nil, false
+	}
+
+	report := &rtcp.CCFeedbackReport{
+		SenderSSRC:      r.senderSSRC,
+		ReportTimestamp: timeToNTPShort(now),
+	}
+
+	for ssrc, packets := range r.rfc8888 {
+		if len(packets) == 0 {
+			delete(r.rfc8888, ssrc)
+			continue
+		}
+		sort.Slice(packets, func(i, j int) bool { return packets[i].sequenceNumber < packets[j].sequenceNumber })
+
+		begin := packets[0].sequenceNumber
+		end := packets[len(packets)-1].sequenceNumber
+		metrics := make([]rtcp.CCFeedbackMetricBlock, end-begin+1)
+
+		byOffset := make(map[uint32]time.Time, len(packets))
+		for _, p := range packets {
+			byOffset[p.sequenceNumber-begin] = p.arrival
+		}
+
+		for offset := range metrics {
+			arrival, ok := byOffset[uint32(offset)]
+			if !ok {
+				continue // gap: zero value already means "not received"
+			}
+			metrics[offset] = rtcp.CCFeedbackMetricBlock{
+				Received:          true,
+				ArrivalTimeOffset: uint16(now.Sub(arrival) / rfc8888TickUnit),
+			}
+		}
+
+		report.ReportBlocks = append(report.ReportBlocks, rtcp.CCFeedbackReportBlock{
+			MediaSSRC:     ssrc,
+			BeginSequence: uint16(begin),
+			MetricBlocks:  metrics,
+		})
+		delete(r.rfc8888, ssrc)
+	}
+
+	return report, true
+}
+
+// timeToNTPShort converts t to the 32-bit compact NTP format (16.16 fixed
+// point seconds since the NTP epoch, modulo 2^16 seconds) used by the
+// RFC 8888 report timestamp field, the format ntpShortToTime reads.
+func timeToNTPShort(t time.Time) uint32 {
+	const ntpEpochOffset = 2208988800 // seconds from 1900-01-01 to 1970-01-01
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) * (1 << 16) / uint64(time.Second)
+	return uint32(seconds)<<16 | uint32(fraction)
+}
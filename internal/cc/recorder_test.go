@@ -0,0 +1,76 @@
+package cc
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRecorderBuildTransportCCFeedback covers the common case: a uniform run
+// of received packets, reported once and then not reported again until more
+// are recorded.
+func TestRecorderBuildTransportCCFeedback(t *testing.T) {
+	r := NewRecorder(7)
+	base := time.Now()
+
+	r.Record(42, 1000, 100, base, 10, true)
+	r.Record(42, 1001, 100, base.Add(10*time.Millisecond), 11, true)
+	r.Record(42, 1002, 100, base.Add(20*time.Millisecond), 12, true)
+
+	report, ok := r.BuildTransportCCFeedback(0)
+	if !ok {
+		t.Fatalf("BuildTransportCCFeedback returned false, want a report")
+	}
+	if report.SenderSSRC != 7 {
+		t.Errorf("SenderSSRC = %d, want 7", report.SenderSSRC)
+	}
+	if report.BaseSequenceNumber != 10 {
+		t.Errorf("BaseSequenceNumber = %d, want 10", report.BaseSequenceNumber)
+	}
+	if report.PacketStatusCount != 3 {
+		t.Errorf("PacketStatusCount = %d, want 3", report.PacketStatusCount)
+	}
+	if len(report.RecvDeltas) != 3 {
+		t.Errorf("got %d RecvDeltas, want 3", len(report.RecvDeltas))
+	}
+
+	if _, ok := r.BuildTransportCCFeedback(1); ok {
+		t.Errorf("BuildTransportCCFeedback returned true on a second call with nothing new recorded")
+	}
+}
+
+// TestRecorderBuildRFC8888Rollover exercises a reporting interval whose
+// packets cross the 16-bit RTP sequence number rollover at 0xffff, so the
+// report must hold a tight 4-entry MetricBlocks slice rather than the
+// ~65534-entry slice a raw uint16 diff would produce.
+func TestRecorderBuildRFC8888Rollover(t *testing.T) {
+	r := NewRecorder(1)
+	base := time.Now()
+
+	r.Record(42, 0xfffe, 100, base, 0, false)
+	r.Record(42, 0x0001, 100, base.Add(time.Millisecond), 0, false)
+
+	report, ok := r.BuildRFC8888(base.Add(2 * time.Millisecond))
+	if !ok {
+		t.Fatalf("BuildRFC8888 returned false, want a report")
+	}
+	if len(report.ReportBlocks) != 1 {
+		t.Fatalf("got %d report blocks, want 1", len(report.ReportBlocks))
+	}
+
+	block := report.ReportBlocks[0]
+	if block.BeginSequence != 0xfffe {
+		t.Errorf("BeginSequence = 0x%x, want 0xfffe", block.BeginSequence)
+	}
+	// Extended sequence numbers run 0xfffe, 0xffff, 0x10000, 0x10001: a
+	// tight 4-entry window, not the ~65534 entries a raw uint16 diff between
+	// 0xfffe and 0x0001 would produce.
+	if len(block.MetricBlocks) != 4 {
+		t.Fatalf("got %d metric blocks, want 4 (0xfffe..0x10001 extended)", len(block.MetricBlocks))
+	}
+	if !block.MetricBlocks[0].Received || !block.MetricBlocks[3].Received {
+		t.Errorf("expected the first and last metric blocks to be marked received")
+	}
+	if block.MetricBlocks[1].Received || block.MetricBlocks[2].Received {
+		t.Errorf("expected the two unreported middle metric blocks to be marked not received")
+	}
+}